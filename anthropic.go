@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const (
+	defaultAnthropicModel  = "claude-3-5-sonnet-latest"
+	anthropicURL           = "https://api.anthropic.com/v1/messages"
+	anthropicVersion       = "2023-06-01"
+	defaultAnthropicTokens = 1024
+)
+
+type anthropicRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// anthropicProvider talks to Anthropic's Messages API.
+type anthropicProvider struct {
+	apiKey string
+}
+
+func (p *anthropicProvider) Name() string { return "anthropic" }
+
+func (p *anthropicProvider) Generate(ctx context.Context, prompt string, opts GenerateOptions) (string, error) {
+	model := opts.Model
+	if model == "" {
+		model = os.Getenv("ANTHROPIC_MODEL")
+	}
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	maxTokens := opts.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultAnthropicTokens
+	}
+
+	reqBody := anthropicRequest{
+		Model:     model,
+		MaxTokens: maxTokens,
+		Messages:  []anthropicMessage{{Role: "user", Content: prompt}},
+	}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := doRequestWithRetry(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", anthropicURL, bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", p.apiKey)
+		req.Header.Set("anthropic-version", anthropicVersion)
+		return req, nil
+	}, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var resp anthropicResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", err
+	}
+	if resp.Error != nil {
+		return "", fmt.Errorf("anthropic API error: %s", resp.Error.Message)
+	}
+	if len(resp.Content) == 0 {
+		return "", fmt.Errorf("no response from Anthropic API")
+	}
+
+	return resp.Content[0].Text, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// fileStat captures per-file change stats parsed out of a unified diff.
+type fileStat struct {
+	path   string
+	adds   int
+	dels   int
+	isNew  bool
+	isTest bool
+}
+
+var diffFileHeader = regexp.MustCompile(`^diff --git a/(.+) b/(.+)$`)
+
+// parseDiffFiles splits a unified diff by its `diff --git` markers and
+// tallies added/removed lines per file.
+func parseDiffFiles(diff string) []fileStat {
+	var files []fileStat
+	var current *fileStat
+
+	for _, line := range strings.Split(diff, "\n") {
+		if m := diffFileHeader.FindStringSubmatch(line); m != nil {
+			if current != nil {
+				files = append(files, *current)
+			}
+			path := m[2]
+			current = &fileStat{path: path, isTest: looksLikeTestFile(path)}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		switch {
+		case line == "new file mode" || strings.HasPrefix(line, "new file mode"):
+			current.isNew = true
+		case strings.HasPrefix(line, "+++") || strings.HasPrefix(line, "---"):
+			// file markers, not content
+		case strings.HasPrefix(line, "+"):
+			current.adds++
+		case strings.HasPrefix(line, "-"):
+			current.dels++
+		}
+	}
+	if current != nil {
+		files = append(files, *current)
+	}
+
+	return files
+}
+
+func looksLikeTestFile(path string) bool {
+	base := filepath.Base(path)
+	return strings.Contains(base, "_test.") ||
+		strings.Contains(path, "/test/") ||
+		strings.HasPrefix(path, "test/") ||
+		strings.Contains(base, ".test.")
+}
+
+func looksLikeDoc(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".md" || ext == ".rst" || strings.HasPrefix(path, "docs/")
+}
+
+// inferCommitType guesses a Conventional-Commits type and scope from the
+// shape of the diff: which paths were touched, whether they're mostly
+// additions or deletions, and whether tests or docs dominate.
+func inferCommitType(diff string) (commitType, scope string) {
+	files := parseDiffFiles(diff)
+	if len(files) == 0 {
+		return "chore", ""
+	}
+
+	allTests, allDocs := true, true
+	totalAdds, totalDels, newFiles := 0, 0, 0
+	for _, f := range files {
+		if !f.isTest {
+			allTests = false
+		}
+		if !looksLikeDoc(f.path) {
+			allDocs = false
+		}
+		totalAdds += f.adds
+		totalDels += f.dels
+		if f.isNew {
+			newFiles++
+		}
+	}
+
+	scope = commonScope(files)
+
+	switch {
+	case allTests:
+		return "test", scope
+	case allDocs:
+		return "docs", scope
+	case newFiles == len(files):
+		return "feat", scope
+	case totalDels > totalAdds*2:
+		return "refactor", scope
+	default:
+		return "fix", scope
+	}
+}
+
+// commonScope returns the shared top-level-ish directory of the touched
+// files, e.g. "auth" for changes confined to internal/auth/*.go. Returns
+// "" when the changes span unrelated directories.
+func commonScope(files []fileStat) string {
+	var dir string
+	for i, f := range files {
+		d := filepath.Dir(f.path)
+		if i == 0 {
+			dir = d
+			continue
+		}
+		if d != dir {
+			return ""
+		}
+	}
+	if dir == "." || dir == "" {
+		return ""
+	}
+	return filepath.Base(dir)
+}
+
+var (
+	conventionalTypes = `feat|fix|refactor|chore|docs|test|perf`
+	conventionalRe    = regexp.MustCompile(`^(?i)(` + conventionalTypes + `)(\([a-zA-Z0-9_/\-]+\))?(!)?: .+`)
+	gitmojiRe         = regexp.MustCompile(`^:[a-z0-9_+\-]+:\s+.+`)
+)
+
+// validateCommitMessage checks that msg matches the schema required by
+// style. The chaotic style only requires the original two-line shape.
+func validateCommitMessage(msg string, style CommitStyle) bool {
+	lines := strings.Split(strings.TrimSpace(msg), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		return false
+	}
+
+	switch style {
+	case StyleConventional:
+		return conventionalRe.MatchString(lines[0])
+	case StyleGitmoji:
+		return gitmojiRe.MatchString(lines[0])
+	default: // StyleChaotic
+		return len(lines) >= 2
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import "testing"
+
+func TestInferCommitType(t *testing.T) {
+	tests := []struct {
+		name      string
+		diff      string
+		wantType  string
+		wantScope string
+	}{
+		{
+			name:      "empty diff is chore",
+			diff:      "",
+			wantType:  "chore",
+			wantScope: "",
+		},
+		{
+			name: "new file is feat",
+			diff: "diff --git a/internal/auth/login.go b/internal/auth/login.go\n" +
+				"new file mode 100644\n" +
+				"+++ b/internal/auth/login.go\n" +
+				"+package auth\n",
+			wantType:  "feat",
+			wantScope: "auth",
+		},
+		{
+			name: "all test files is test",
+			diff: "diff --git a/internal/auth/login_test.go b/internal/auth/login_test.go\n" +
+				"+++ b/internal/auth/login_test.go\n" +
+				"+func TestLogin(t *testing.T) {}\n",
+			wantType:  "test",
+			wantScope: "auth",
+		},
+		{
+			name: "all markdown is docs",
+			diff: "diff --git a/docs/readme.md b/docs/readme.md\n" +
+				"+++ b/docs/readme.md\n" +
+				"+Some docs.\n",
+			wantType:  "docs",
+			wantScope: "docs",
+		},
+		{
+			name: "mostly deletions is refactor",
+			diff: "diff --git a/internal/auth/login.go b/internal/auth/login.go\n" +
+				"+++ b/internal/auth/login.go\n" +
+				"-line one\n" +
+				"-line two\n" +
+				"-line three\n" +
+				"+line one\n",
+			wantType:  "refactor",
+			wantScope: "auth",
+		},
+		{
+			name: "mixed edits default to fix",
+			diff: "diff --git a/internal/auth/login.go b/internal/auth/login.go\n" +
+				"+++ b/internal/auth/login.go\n" +
+				"+fixed line\n" +
+				"-broken line\n",
+			wantType:  "fix",
+			wantScope: "auth",
+		},
+		{
+			name: "scope is empty when files span directories",
+			diff: "diff --git a/internal/auth/login.go b/internal/auth/login.go\n" +
+				"+++ b/internal/auth/login.go\n" +
+				"+fixed line\n" +
+				"diff --git a/internal/db/conn.go b/internal/db/conn.go\n" +
+				"+++ b/internal/db/conn.go\n" +
+				"-broken line\n",
+			wantType:  "fix",
+			wantScope: "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gotType, gotScope := inferCommitType(tc.diff)
+			if gotType != tc.wantType || gotScope != tc.wantScope {
+				t.Errorf("inferCommitType(%q) = (%q, %q), want (%q, %q)", tc.diff, gotType, gotScope, tc.wantType, tc.wantScope)
+			}
+		})
+	}
+}
+
+func TestValidateCommitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		msg   string
+		style CommitStyle
+		want  bool
+	}{
+		{"chaotic needs two lines", "🎸 just one line", StyleChaotic, false},
+		{"chaotic two lines ok", "🎸 line one\nline two", StyleChaotic, true},
+		{"conventional matches schema", "feat(auth): add login", StyleConventional, true},
+		{"conventional rejects chaotic shape", "🎸 line one\nline two", StyleConventional, false},
+		{"conventional allows breaking bang", "fix(auth)!: drop legacy flow", StyleConventional, true},
+		{"gitmoji matches schema", ":sparkles: add login", StyleGitmoji, true},
+		{"gitmoji rejects missing emoji code", "sparkles add login", StyleGitmoji, false},
+		{"empty message always rejected", "", StyleConventional, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := validateCommitMessage(tc.msg, tc.style); got != tc.want {
+				t.Errorf("validateCommitMessage(%q, %q) = %v, want %v", tc.msg, tc.style, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CommitStyle selects which schema the generated commit message must match.
+type CommitStyle string
+
+const (
+	StyleChaotic      CommitStyle = "chaotic"
+	StyleConventional CommitStyle = "conventional"
+	StyleGitmoji      CommitStyle = "gitmoji"
+)
+
+const defaultMaxRegenerate = 3
+
+// Config holds user preferences loaded from ~/.genie-fun.yaml.
+type Config struct {
+	Style            CommitStyle `yaml:"style"`
+	MaxRegenerate    int         `yaml:"max_regenerate"`
+	MaxPromptTokens  int         `yaml:"max_prompt_tokens"`
+	IncludeGenerated bool        `yaml:"include_generated"`
+}
+
+func defaultConfig() *Config {
+	return &Config{
+		Style:           StyleChaotic,
+		MaxRegenerate:   defaultMaxRegenerate,
+		MaxPromptTokens: defaultMaxPromptTokens,
+	}
+}
+
+// loadConfig reads ~/.genie-fun.yaml if present, falling back to defaults
+// for any field that is missing or the file itself being absent.
+func loadConfig() (*Config, error) {
+	cfg := defaultConfig()
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(home, ".genie-fun.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return cfg, err
+	}
+
+	if cfg.Style == "" {
+		cfg.Style = StyleChaotic
+	}
+	if cfg.MaxRegenerate <= 0 {
+		cfg.MaxRegenerate = defaultMaxRegenerate
+	}
+
+	return cfg, nil
+}
+
+func isValidStyle(style CommitStyle) bool {
+	switch style {
+	case StyleChaotic, StyleConventional, StyleGitmoji:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultMaxPromptTokens  = 6000
+	defaultHunkPreviewLines = 12
+)
+
+// fileDiff is a single file's slice of a unified diff, split out so it
+// can be rendered, summarized, or dropped independently of the rest of
+// the diff.
+type fileDiff struct {
+	path      string
+	language  string
+	adds      int
+	dels      int
+	isNew     bool
+	isDeleted bool
+	hunks     []string // each entry is one complete "@@ ... @@" hunk, never partial
+}
+
+var skipBasenames = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"Cargo.lock":        true,
+	"composer.lock":     true,
+	"Gemfile.lock":      true,
+}
+
+// isLockfileOrVendor reports whether path is one of the dependency
+// lockfiles or vendor directories we skip from the prompt by default,
+// since they're noise an LLM can't meaningfully summarize.
+func isLockfileOrVendor(path string) bool {
+	if skipBasenames[filepath.Base(path)] {
+		return true
+	}
+	return strings.Contains(path, "vendor/") || strings.Contains(path, "node_modules/")
+}
+
+// loadGeneratedPatterns reads .gitattributes (if present) in the current
+// directory and returns the path patterns marked linguist-generated.
+func loadGeneratedPatterns() []string {
+	data, err := os.ReadFile(".gitattributes")
+	if err != nil {
+		return nil
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		for _, attr := range fields[1:] {
+			if attr == "linguist-generated" || attr == "linguist-generated=true" {
+				patterns = append(patterns, fields[0])
+				break
+			}
+		}
+	}
+	return patterns
+}
+
+func isGenerated(path string, patterns []string) bool {
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, path); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(p, filepath.Base(path)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func languageForPath(path string) string {
+	switch filepath.Ext(path) {
+	case ".go":
+		return "Go"
+	case ".js", ".jsx":
+		return "JavaScript"
+	case ".ts", ".tsx":
+		return "TypeScript"
+	case ".py":
+		return "Python"
+	case ".rs":
+		return "Rust"
+	case ".java":
+		return "Java"
+	case ".rb":
+		return "Ruby"
+	case ".md":
+		return "Markdown"
+	case ".yaml", ".yml":
+		return "YAML"
+	case ".json":
+		return "JSON"
+	default:
+		return "Other"
+	}
+}
+
+// splitDiffByFile parses a unified diff into one fileDiff per touched
+// file, with each hunk kept as a single intact string so later stages
+// can drop or summarize whole hunks without ever slicing one in half.
+func splitDiffByFile(diff string) []fileDiff {
+	var result []fileDiff
+	var cur *fileDiff
+	var curHunk []string
+
+	flushHunk := func() {
+		if cur != nil && len(curHunk) > 0 {
+			cur.hunks = append(cur.hunks, strings.Join(curHunk, "\n"))
+		}
+		curHunk = nil
+	}
+	flushFile := func() {
+		flushHunk()
+		if cur != nil {
+			result = append(result, *cur)
+		}
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		if m := diffFileHeader.FindStringSubmatch(line); m != nil {
+			flushFile()
+			path := m[2]
+			cur = &fileDiff{path: path, language: languageForPath(path)}
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			cur.isNew = true
+		case strings.HasPrefix(line, "deleted file mode"):
+			cur.isDeleted = true
+		case strings.HasPrefix(line, "@@"):
+			flushHunk()
+			curHunk = append(curHunk, line)
+		case curHunk != nil:
+			curHunk = append(curHunk, line)
+			switch {
+			case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
+				cur.adds++
+			case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
+				cur.dels++
+			}
+		}
+	}
+	flushFile()
+
+	return result
+}
+
+// estimateTokens is a rough chars/4 heuristic, good enough for deciding
+// whether to summarize without pulling in a real tokenizer.
+func estimateTokens(s string) int {
+	return len(s)/4 + 1
+}
+
+// summarizeHunk keeps the hunk's first maxLines lines (its "@@" header
+// plus that many lines of context/changes) and marks how many more
+// lines were collapsed, rather than shipping the whole thing.
+func summarizeHunk(hunk string, maxLines int) string {
+	lines := strings.Split(hunk, "\n")
+	if len(lines) <= maxLines {
+		return hunk
+	}
+	kept := strings.Join(lines[:maxLines], "\n")
+	return fmt.Sprintf("%s\n…(%d more lines)", kept, len(lines)-maxLines)
+}
+
+// buildDiffBundle turns a raw diff into the text that actually gets
+// embedded in the generation prompt: lockfiles/vendor/generated files
+// are skipped by default, and if the result would still blow past
+// maxPromptTokens, hunks are summarized and then, if necessary, whole
+// files are dropped from the tail — filenames and hunk boundaries are
+// always preserved, nothing is cut mid-hunk.
+func buildDiffBundle(diff string, includeGenerated bool, maxPromptTokens int) string {
+	if maxPromptTokens <= 0 {
+		maxPromptTokens = defaultMaxPromptTokens
+	}
+
+	generatedPatterns := loadGeneratedPatterns()
+	files := splitDiffByFile(diff)
+
+	// getGitDiff's untracked-files fallback (and anything else that
+	// doesn't carry `diff --git` markers) has no files for
+	// splitDiffByFile to find. Pass it through as-is rather than
+	// collapsing it to an empty bundle.
+	if len(files) == 0 {
+		return diff
+	}
+
+	var kept []fileDiff
+	var skipped []string
+	for _, f := range files {
+		if !includeGenerated && (isLockfileOrVendor(f.path) || isGenerated(f.path, generatedPatterns)) {
+			skipped = append(skipped, f.path)
+			continue
+		}
+		kept = append(kept, f)
+	}
+
+	if bundle := renderDiffBundle(kept, skipped); estimateTokens(bundle) <= maxPromptTokens {
+		return bundle
+	}
+
+	summarized := make([]fileDiff, len(kept))
+	for i, f := range kept {
+		sf := f
+		sf.hunks = make([]string, len(f.hunks))
+		for j, h := range f.hunks {
+			sf.hunks[j] = summarizeHunk(h, defaultHunkPreviewLines)
+		}
+		summarized[i] = sf
+	}
+
+	bundle := renderDiffBundle(summarized, skipped)
+	for estimateTokens(bundle) > maxPromptTokens && len(summarized) > 0 {
+		dropped := summarized[len(summarized)-1]
+		summarized = summarized[:len(summarized)-1]
+		skipped = append(skipped, dropped.path+" (dropped to fit prompt budget)")
+		bundle = renderDiffBundle(summarized, skipped)
+	}
+
+	return bundle
+}
+
+func renderDiffBundle(files []fileDiff, skipped []string) string {
+	var b strings.Builder
+	for _, f := range files {
+		status := "modified"
+		switch {
+		case f.isNew:
+			status = "new file"
+		case f.isDeleted:
+			status = "deleted"
+		}
+		fmt.Fprintf(&b, "### %s (%s, %s, +%d/-%d)\n", f.path, f.language, status, f.adds, f.dels)
+		for _, h := range f.hunks {
+			b.WriteString(h)
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
+	if len(skipped) > 0 {
+		b.WriteString("(skipped from prompt: " + strings.Join(skipped, ", ") + ")\n")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,152 @@
+package main
+
+import "strings"
+
+import "testing"
+
+func TestSplitDiffByFile(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n" +
+		"@@ -1,2 +1,3 @@\n" +
+		"-old line\n" +
+		"+new line\n" +
+		"+another new line\n" +
+		"diff --git a/new.go b/new.go\n" +
+		"new file mode 100644\n" +
+		"@@ -0,0 +1,1 @@\n" +
+		"+package main\n"
+
+	files := splitDiffByFile(diff)
+	if len(files) != 2 {
+		t.Fatalf("got %d files, want 2", len(files))
+	}
+
+	if files[0].path != "main.go" || files[0].adds != 2 || files[0].dels != 1 {
+		t.Errorf("main.go stats = %+v, want path=main.go adds=2 dels=1", files[0])
+	}
+	if len(files[0].hunks) != 1 {
+		t.Errorf("main.go hunks = %d, want 1", len(files[0].hunks))
+	}
+
+	if !files[1].isNew {
+		t.Errorf("new.go should be marked isNew")
+	}
+	if files[1].adds != 1 {
+		t.Errorf("new.go adds = %d, want 1", files[1].adds)
+	}
+}
+
+func TestSplitDiffByFileNeverSplitsAHunk(t *testing.T) {
+	diff := "diff --git a/main.go b/main.go\n" +
+		"@@ -1,2 +1,2 @@\n" +
+		" context line\n" +
+		"-old\n" +
+		"+new\n"
+
+	files := splitDiffByFile(diff)
+	if len(files) != 1 || len(files[0].hunks) != 1 {
+		t.Fatalf("want exactly one file with one hunk, got %+v", files)
+	}
+
+	hunk := files[0].hunks[0]
+	if !strings.HasPrefix(hunk, "@@") {
+		t.Errorf("hunk should start with its @@ header, got %q", hunk)
+	}
+	for _, line := range []string{"context line", "-old", "+new"} {
+		if !strings.Contains(hunk, line) {
+			t.Errorf("hunk %q missing expected line %q", hunk, line)
+		}
+	}
+}
+
+func TestSummarizeHunkKeepsShortHunksIntact(t *testing.T) {
+	hunk := "@@ -1,2 +1,2 @@\n-old\n+new"
+	if got := summarizeHunk(hunk, 12); got != hunk {
+		t.Errorf("short hunk should be returned unchanged, got %q", got)
+	}
+}
+
+func TestSummarizeHunkTruncatesLongHunks(t *testing.T) {
+	var lines []string
+	for i := 0; i < 20; i++ {
+		lines = append(lines, "+line")
+	}
+	hunk := "@@ -1,20 +1,20 @@\n" + strings.Join(lines, "\n")
+
+	got := summarizeHunk(hunk, 5)
+
+	if !strings.HasPrefix(got, "@@ -1,20 +1,20 @@") {
+		t.Errorf("summarized hunk should keep the @@ header, got %q", got)
+	}
+	if !strings.Contains(got, "more lines") {
+		t.Errorf("summarized hunk should note how many lines were dropped, got %q", got)
+	}
+	if strings.Count(got, "\n") >= strings.Count(hunk, "\n") {
+		t.Errorf("summarized hunk should be shorter than the original")
+	}
+}
+
+func TestBuildDiffBundleSkipsLockfilesByDefault(t *testing.T) {
+	diff := "diff --git a/go.sum b/go.sum\n" +
+		"@@ -1,1 +1,1 @@\n" +
+		"-old hash\n" +
+		"+new hash\n" +
+		"diff --git a/main.go b/main.go\n" +
+		"@@ -1,1 +1,1 @@\n" +
+		"-old\n" +
+		"+new\n"
+
+	bundle := buildDiffBundle(diff, false, defaultMaxPromptTokens)
+
+	if strings.Contains(bundle, "old hash") {
+		t.Errorf("lockfile contents should be skipped by default, got %q", bundle)
+	}
+	if !strings.Contains(bundle, "go.sum") {
+		t.Errorf("skipped lockfile should still be named in the bundle, got %q", bundle)
+	}
+	if !strings.Contains(bundle, "main.go") {
+		t.Errorf("non-lockfile changes should still be rendered, got %q", bundle)
+	}
+}
+
+func TestBuildDiffBundlePassesThroughNonUnifiedDiffInput(t *testing.T) {
+	// This is the shape getGitDiff's untracked-files fallback produces:
+	// no `diff --git` markers, so splitDiffByFile finds no files.
+	fallback := "New untracked files:\n+ foo.go\n"
+
+	bundle := buildDiffBundle(fallback, false, defaultMaxPromptTokens)
+
+	if bundle != fallback {
+		t.Errorf("buildDiffBundle(%q) = %q, want it passed through unchanged", fallback, bundle)
+	}
+}
+
+func TestBuildDiffBundleIncludeGeneratedOverride(t *testing.T) {
+	diff := "diff --git a/go.sum b/go.sum\n" +
+		"@@ -1,1 +1,1 @@\n" +
+		"-old hash\n" +
+		"+new hash\n"
+
+	bundle := buildDiffBundle(diff, true, defaultMaxPromptTokens)
+
+	if !strings.Contains(bundle, "old hash") {
+		t.Errorf("--include-generated should keep lockfile contents, got %q", bundle)
+	}
+}
+
+func TestBuildDiffBundleSummarizesWhenOverBudget(t *testing.T) {
+	var lines []string
+	for i := 0; i < 50; i++ {
+		lines = append(lines, "+some reasonably long line of code that adds up")
+	}
+	diff := "diff --git a/main.go b/main.go\n@@ -1,50 +1,50 @@\n" + strings.Join(lines, "\n") + "\n"
+
+	full := buildDiffBundle(diff, false, 1_000_000)
+	budgeted := buildDiffBundle(diff, false, 20)
+
+	if !strings.Contains(budgeted, "main.go") {
+		t.Errorf("filename should survive summarization, got %q", budgeted)
+	}
+	if len(budgeted) >= len(full) {
+		t.Errorf("budgeted bundle (%d bytes) should be smaller than the full one (%d bytes)", len(budgeted), len(full))
+	}
+}
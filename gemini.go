@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const (
+	defaultGeminiModel = "gemini-2.0-flash"
+	geminiBaseURL      = "https://generativelanguage.googleapis.com/v1beta/models/"
+)
+
+type GeminiRequest struct {
+	Contents []Content `json:"contents"`
+}
+
+type Content struct {
+	Parts []Part `json:"parts"`
+}
+
+type Part struct {
+	Text string `json:"text"`
+}
+
+type GeminiResponse struct {
+	Candidates []Candidate `json:"candidates"`
+	Error      *ErrorInfo  `json:"error,omitempty"`
+}
+
+type Candidate struct {
+	Content ContentResponse `json:"content"`
+}
+
+type ContentResponse struct {
+	Parts []PartResponse `json:"parts"`
+}
+
+type PartResponse struct {
+	Text string `json:"text"`
+}
+
+type ErrorInfo struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// geminiProvider talks to Google's Generative Language API.
+type geminiProvider struct {
+	apiKey string
+}
+
+func (p *geminiProvider) Name() string { return "gemini" }
+
+func (p *geminiProvider) Generate(ctx context.Context, prompt string, opts GenerateOptions) (string, error) {
+	model := opts.Model
+	if model == "" {
+		model = os.Getenv("GEMINI_MODEL")
+	}
+	if model == "" {
+		model = defaultGeminiModel
+	}
+	url := geminiBaseURL + model + ":generateContent?key=" + p.apiKey
+
+	reqBody := GeminiRequest{
+		Contents: []Content{{Parts: []Part{{Text: prompt}}}},
+	}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := doRequestWithRetry(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var geminiResp GeminiResponse
+	if err := json.Unmarshal(body, &geminiResp); err != nil {
+		return "", err
+	}
+	if geminiResp.Error != nil {
+		return "", fmt.Errorf("gemini API error: %s", geminiResp.Error.Message)
+	}
+	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no response from Gemini API")
+	}
+
+	return geminiResp.Candidates[0].Content.Parts[0].Text, nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker is embedded in every hook script genie-fun installs so
+// uninstall-hook (and a re-run of install-hook) can tell a genie-fun
+// hook apart from one the user wrote or installed by another tool.
+const hookMarker = "# installed by genie-fun install-hook"
+
+const hookScriptTemplate = `#!/bin/sh
+%s
+# Writes a suggested commit message into $1 (the commit-msg file) when
+# it's still empty. Skips merge/squash/template commits, since those
+# already have a message git generated for them. Remove with
+# ` + "`genie-fun uninstall-hook`" + `.
+
+case "$2" in
+  merge|squash|template)
+    exit 0
+    ;;
+esac
+
+if [ -s "$1" ]; then
+  exit 0
+fi
+
+msg=$(%q suggest 2>/dev/null)
+if [ -n "$msg" ]; then
+  printf '%%s\n' "$msg" > "$1"
+fi
+`
+
+// runInstallHook implements `genie-fun install-hook`: it writes a
+// prepare-commit-msg hook into the current repo's hooks directory that
+// shells out to `genie-fun suggest`.
+func runInstallHook(args []string) {
+	fs := flag.NewFlagSet("install-hook", flag.ExitOnError)
+	force := fs.Bool("force", false, "overwrite an existing prepare-commit-msg hook that genie-fun didn't install")
+	fs.Parse(args)
+
+	hookPath, err := prepareCommitMsgHookPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if existing, err := os.ReadFile(hookPath); err == nil {
+		if !strings.Contains(string(existing), hookMarker) && !*force {
+			fmt.Fprintf(os.Stderr, "❌ %s already exists and wasn't installed by genie-fun; rerun with --force to overwrite\n", hookPath)
+			os.Exit(1)
+		}
+	}
+
+	exePath, err := os.Executable()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Could not locate genie-fun executable: %v\n", err)
+		os.Exit(1)
+	}
+
+	script := fmt.Sprintf(hookScriptTemplate, hookMarker, exePath)
+	if err := os.WriteFile(hookPath, []byte(script), 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error writing hook: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Installed prepare-commit-msg hook at %s\n", hookPath)
+}
+
+// runUninstallHook implements `genie-fun uninstall-hook`: it removes the
+// prepare-commit-msg hook, but only if genie-fun installed it.
+func runUninstallHook(args []string) {
+	fs := flag.NewFlagSet("uninstall-hook", flag.ExitOnError)
+	fs.Parse(args)
+
+	hookPath, err := prepareCommitMsgHookPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	data, err := os.ReadFile(hookPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("✨ No prepare-commit-msg hook installed.")
+			return
+		}
+		fmt.Fprintf(os.Stderr, "❌ Error reading hook: %v\n", err)
+		os.Exit(1)
+	}
+
+	if !strings.Contains(string(data), hookMarker) {
+		fmt.Fprintf(os.Stderr, "❌ %s wasn't installed by genie-fun, leaving it in place\n", hookPath)
+		os.Exit(1)
+	}
+
+	if err := os.Remove(hookPath); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error removing hook: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("✅ Removed prepare-commit-msg hook")
+}
+
+// prepareCommitMsgHookPath resolves the prepare-commit-msg path inside
+// the current repo's (possibly worktree-relocated) hooks directory via
+// `git rev-parse --git-dir`, rather than assuming ".git/hooks".
+func prepareCommitMsgHookPath() (string, error) {
+	out, err := exec.Command("git", "rev-parse", "--git-dir").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository")
+	}
+
+	gitDir := strings.TrimSpace(string(out))
+	return filepath.Join(gitDir, "hooks", "prepare-commit-msg"), nil
+}
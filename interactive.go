@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// commitSession drives the interactive review/edit/commit loop that runs
+// after a commit message has been generated, unless --yes or --dry-run
+// short-circuited it.
+type commitSession struct {
+	provider    Provider
+	gen         generationInput
+	style       CommitStyle
+	maxAttempts int
+	message     string
+}
+
+// run shows the current message and repeatedly prompts the user to
+// commit, edit, regenerate, switch styles, or quit.
+func (s *commitSession) run() error {
+	reader := bufio.NewReader(os.Stdin)
+
+	for {
+		fmt.Println(s.message)
+		fmt.Print("\n[c]ommit / [e]dit / [r]egenerate / [s]tyle-switch / [q]uit: ")
+
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("reading input: %w", err)
+		}
+
+		switch firstRune(line) {
+		case 'c':
+			if err := doGitCommit(s.message); err != nil {
+				return fmt.Errorf("committing: %w", err)
+			}
+			fmt.Println("✅ Committed!")
+			return nil
+
+		case 'e':
+			edited, err := openInEditor(s.message)
+			if err != nil {
+				fmt.Printf("⚠️  Could not open editor: %v\n", err)
+				continue
+			}
+			s.message = edited
+
+		case 'r':
+			fmt.Print("Nudge (e.g. \"make it weirder\", \"more serious\", blank for none): ")
+			nudge, _ := reader.ReadString('\n')
+			msg, err := generateCommitWithRetries(context.Background(), s.provider, s.gen, s.style, s.maxAttempts, trimNewline(nudge))
+			if err != nil {
+				fmt.Printf("⚠️  Could not regenerate: %v\n", err)
+				continue
+			}
+			s.message = msg
+
+		case 's':
+			style, err := promptForStyle(reader)
+			if err != nil {
+				fmt.Printf("⚠️  %v\n", err)
+				continue
+			}
+			s.style = style
+			msg, err := generateCommitWithRetries(context.Background(), s.provider, s.gen, s.style, s.maxAttempts, "")
+			if err != nil {
+				fmt.Printf("⚠️  Could not regenerate: %v\n", err)
+				continue
+			}
+			s.message = msg
+
+		case 'q':
+			fmt.Println("👋 Quitting without committing.")
+			return nil
+
+		default:
+			fmt.Println("Didn't catch that, try c/e/r/s/q.")
+		}
+	}
+}
+
+func promptForStyle(reader *bufio.Reader) (CommitStyle, error) {
+	fmt.Print("Style [chaotic/conventional/gitmoji]: ")
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	style := CommitStyle(trimNewline(line))
+	if !isValidStyle(style) {
+		return "", fmt.Errorf("unknown style %q", style)
+	}
+	return style, nil
+}
+
+// doGitCommit commits msg as-is, using `-F` for multi-line messages so
+// git doesn't mangle embedded newlines the way repeated `-m` flags would.
+func doGitCommit(msg string) error {
+	if !containsNewline(msg) {
+		cmd := exec.Command("git", "commit", "-m", msg)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+
+	tmpfile, err := os.CreateTemp("", "genie-fun-commit-*.txt")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.WriteString(msg); err != nil {
+		tmpfile.Close()
+		return err
+	}
+	if err := tmpfile.Close(); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("git", "commit", "-F", tmpfile.Name())
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// openInEditor writes initial to a temp file, opens $EDITOR (falling
+// back to vi) on it, and returns the edited contents. $EDITOR is split
+// on whitespace first, since real-world values like "code -w" or "vim
+// -u NONE" carry flags that exec.Command can't treat as part of the
+// binary name.
+func openInEditor(initial string) (string, error) {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	editorArgs := strings.Fields(editor)
+
+	tmpfile, err := os.CreateTemp("", "genie-fun-edit-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmpfile.Name())
+
+	if _, err := tmpfile.WriteString(initial); err != nil {
+		tmpfile.Close()
+		return "", err
+	}
+	if err := tmpfile.Close(); err != nil {
+		return "", err
+	}
+
+	cmd := exec.Command(editorArgs[0], append(editorArgs[1:], tmpfile.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(tmpfile.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return trimNewline(string(edited)), nil
+}
+
+func firstRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return 0
+}
+
+func trimNewline(s string) string {
+	for len(s) > 0 && (s[len(s)-1] == '\n' || s[len(s)-1] == '\r') {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+func containsNewline(s string) bool {
+	for _, r := range s {
+		if r == '\n' {
+			return true
+		}
+	}
+	return false
+}
@@ -1,89 +1,144 @@
 package main
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
+	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"os"
 	"os/exec"
-	"runtime"
 	"strings"
-	"time"
 )
 
 const (
-	appName   = "genie-fun"
-	version   = "1.0.0"
-	geminiURL = "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.0-flash:generateContent"
+	appName = "genie-fun"
+	version = "1.0.0"
 )
 
-type GeminiRequest struct {
-	Contents []Content `json:"contents"`
-}
+// main dispatches to the genie-fun subcommand suite. With no subcommand
+// (or a first arg that isn't one of the names below) it falls back to
+// `commit`, the original one-shot generate/commit flow, so existing
+// invocations and flags keep working unchanged.
+func main() {
+	args := os.Args[1:]
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "install-hook":
+			runInstallHook(args[1:])
+			return
+		case "uninstall-hook":
+			runUninstallHook(args[1:])
+			return
+		case "suggest":
+			runSuggest(args[1:])
+			return
+		case "commit":
+			args = args[1:]
+		}
+	}
 
-type Content struct {
-	Parts []Part `json:"parts"`
+	runCommit(args)
 }
 
-type Part struct {
-	Text string `json:"text"`
+// generateFlags are shared by the `commit` and `suggest` subcommands.
+type generateFlags struct {
+	style            *string
+	provider         *string
+	includeGenerated *bool
+	maxPromptTokens  *int
 }
 
-type GeminiResponse struct {
-	Candidates []Candidate `json:"candidates"`
-	Error      *ErrorInfo  `json:"error,omitempty"`
+func newGenerateFlagSet(name string) (*flag.FlagSet, *generateFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	gf := &generateFlags{
+		style:            fs.String("style", "", "commit message style: chaotic, conventional, gitmoji (overrides config)"),
+		provider:         fs.String("provider", "", "LLM backend: gemini, openai, anthropic, ollama (overrides GENIE_PROVIDER/config)"),
+		includeGenerated: fs.Bool("include-generated", false, "include linguist-generated files and lockfiles/vendor in the prompt"),
+		maxPromptTokens:  fs.Int("max-prompt-tokens", 0, "cap the diff bundle fed to the model (overrides config, 0 = use config/default)"),
+	}
+	return fs, gf
 }
 
-type Candidate struct {
-	Content ContentResponse `json:"content"`
+// generationInput bundles what's needed to generate a commit message:
+// rawDiff is the actual `git diff` output, used for type/scope
+// classification, while promptDiff is the (possibly chunked and
+// summarized) bundle that actually gets embedded in the LLM prompt.
+type generationInput struct {
+	rawDiff    string
+	promptDiff string
 }
 
-type ContentResponse struct {
-	Parts []PartResponse `json:"parts"`
-}
+// prepareGeneration loads config (yaml + repo/global JSON overrides),
+// resolves the provider and diff bundle shared by `commit` and
+// `suggest`, and returns everything generateCommitWithRetries needs.
+func prepareGeneration(gf *generateFlags) (Provider, *Config, CommitStyle, generationInput, error) {
+	if !isGitRepo() {
+		return nil, nil, "", generationInput{}, fmt.Errorf("not a git repository")
+	}
 
-type PartResponse struct {
-	Text string `json:"text"`
-}
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, nil, "", generationInput{}, fmt.Errorf("loading config: %w", err)
+	}
 
-type ErrorInfo struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-}
+	styleOverride, providerOverride := *gf.style, *gf.provider
+	applyRepoOverrides(&styleOverride, &providerOverride)
 
-func main() {
-	// Check if we're in a git repository
-	if !isGitRepo() {
-		fmt.Fprintln(os.Stderr, "❌ Not a git repository")
-		os.Exit(1)
+	style := cfg.Style
+	if styleOverride != "" {
+		style = CommitStyle(styleOverride)
+	}
+	if !isValidStyle(style) {
+		return nil, nil, "", generationInput{}, fmt.Errorf("unknown style %q (want chaotic, conventional, or gitmoji)", style)
 	}
 
-	// Get API key from environment
-	apiKey := os.Getenv("GOOGLE_AI_TOKEN")
-	if apiKey == "" {
-		fmt.Fprintln(os.Stderr, "❌ GOOGLE_AI_TOKEN environment variable not set")
-		fmt.Fprintln(os.Stderr, "   Get your API key from: https://aistudio.google.com/apikey")
-		os.Exit(1)
+	provider, err := newProvider(resolveProviderName(providerOverride))
+	if err != nil {
+		return nil, nil, "", generationInput{}, err
+	}
+
+	rawDiff, err := getGitDiff()
+	if err != nil {
+		return nil, nil, "", generationInput{}, fmt.Errorf("getting git diff: %w", err)
+	}
+	if strings.TrimSpace(rawDiff) == "" {
+		return provider, cfg, style, generationInput{}, nil
+	}
+
+	includeGenerated := cfg.IncludeGenerated || *gf.includeGenerated
+	maxPromptTokens := cfg.MaxPromptTokens
+	if *gf.maxPromptTokens > 0 {
+		maxPromptTokens = *gf.maxPromptTokens
 	}
+	promptDiff := buildDiffBundle(rawDiff, includeGenerated, maxPromptTokens)
 
-	// Get git diff
-	diff, err := getGitDiff()
+	return provider, cfg, style, generationInput{rawDiff: rawDiff, promptDiff: promptDiff}, nil
+}
+
+// runCommit implements the `genie-fun commit` subcommand (and the
+// no-subcommand default): generate a message, then either print it
+// (--dry-run), commit it straight away (--yes), or drop into the
+// interactive review loop.
+func runCommit(args []string) {
+	fs, gf := newGenerateFlagSet("commit")
+	yesFlag := fs.Bool("yes", false, "commit the generated message automatically, no prompts")
+	dryRunFlag := fs.Bool("dry-run", false, "only print the generated message, never commit")
+	fs.Parse(args)
+
+	provider, cfg, style, gen, err := prepareGeneration(gf)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "❌ Error getting git diff: %v\n", err)
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
 		os.Exit(1)
 	}
 
-	if strings.TrimSpace(diff) == "" {
+	if gen.rawDiff == "" {
 		fmt.Println("✨ No changes detected. Nothing to commit!")
 		return
 	}
 
-	fmt.Println("🎲 Generating creative commit message...")
+	fmt.Printf("🎲 Generating creative commit message via %s...\n", provider.Name())
 
-	// Generate commit message
-	commitMsg, err := generateCreativeCommit(apiKey, diff)
+	commitMsg, err := generateCommitWithRetries(context.Background(), provider, gen, style, cfg.MaxRegenerate, "")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Error generating commit: %v\n", err)
 		os.Exit(1)
@@ -92,13 +147,85 @@ func main() {
 	// Display the generated commit message
 	fmt.Println("\n" + commitMsg + "\n")
 
-	// Copy to clipboard
-	err = copyToClipboard(commitMsg)
+	if *dryRunFlag {
+		return
+	}
+
+	if *yesFlag {
+		if err := doGitCommit(commitMsg); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Error committing: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅ Committed!")
+		return
+	}
+
+	session := &commitSession{
+		provider:    provider,
+		gen:         gen,
+		style:       style,
+		maxAttempts: cfg.MaxRegenerate,
+		message:     commitMsg,
+	}
+	if err := session.run(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runSuggest implements `genie-fun suggest`: it prints nothing but the
+// generated commit message (or nothing at all, on empty diff/error) so
+// it's safe to embed as `$(genie-fun suggest)` from the prepare-commit-msg
+// hook or other scripts.
+func runSuggest(args []string) {
+	fs, gf := newGenerateFlagSet("suggest")
+	fs.Parse(args)
+
+	provider, cfg, style, gen, err := prepareGeneration(gf)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		os.Exit(1)
+	}
+	if gen.rawDiff == "" {
+		return
+	}
+
+	commitMsg, err := generateCommitWithRetries(context.Background(), provider, gen, style, cfg.MaxRegenerate, "")
 	if err != nil {
-		fmt.Printf("📋 Could not copy to clipboard: %v\n", err)
-	} else {
-		fmt.Println("📋 Copied to clipboard!")
+		fmt.Fprintf(os.Stderr, "❌ Error generating commit: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println(commitMsg)
+}
+
+// generateCommitWithRetries asks the model for a commit message matching
+// style, regenerating up to maxAttempts times if the response doesn't
+// match the expected schema. The last response is returned even if it
+// never validates, so the user always gets something to look at.
+// Type/scope are inferred from gen.rawDiff (the real `git diff`), never
+// from gen.promptDiff, since the rendered bundle's "### path (...)"
+// headers don't match the `diff --git` markers the classifier looks for.
+func generateCommitWithRetries(ctx context.Context, provider Provider, gen generationInput, style CommitStyle, maxAttempts int, nudge string) (string, error) {
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxRegenerate
+	}
+
+	commitType, scope := inferCommitType(gen.rawDiff)
+
+	var last string
+	var err error
+	for i := 0; i < maxAttempts; i++ {
+		last, err = generateCreativeCommit(ctx, provider, gen.promptDiff, style, commitType, scope, nudge)
+		if err != nil {
+			return "", err
+		}
+		if validateCommitMessage(last, style) {
+			return last, nil
+		}
 	}
+
+	return last, nil
 }
 
 func isGitRepo() bool {
@@ -155,125 +282,24 @@ func getGitDiff() (string, error) {
 	return "", nil
 }
 
-func generateCreativeCommit(apiKey, diff string) (string, error) {
-	prompt := `You are a creative, witty, and slightly chaotic developer who treats commit messages as an art form. You make commits that are fun, random, and creative - but ALWAYS contextually relevant to the actual code changes.
-
-YOUR MISSION:
-Analyze the git diff and create a TWO-LINE commit message:
-- Line 1: Random emoji + creative/funny/philosophical/lyrical message related to the change
-- Line 2: Actual technical explanation of what changed
-
-CREATIVE STYLES (pick randomly based on the vibe):
-🎵 SONG LYRICS: Find a song lyric that metaphorically relates to the change
-  Example: "🎸 I fought the law and the law won / Fixed authentication middleware to properly validate JWT tokens"
-
-🧠 PHILOSOPHICAL: Drop some wisdom that somehow connects
-  Example: "🌊 The only constant is change, except constants which I just changed / Refactored configuration values to environment variables"
-
-😂 JOKES/PUNS: Make a programming joke or pun about the change
-  Example: "🤡 Why did the function break up? It had too many arguments! / Simplified parameter passing in user service"
-
-🎭 RANDOM FACTS: Share a random fact that loosely relates
-  Example: "🦖 T-Rex couldn't clap but this code now can / Added applause animation to success notifications"
-
-🎪 CHAOS: Just pure creative chaos that somehow makes sense
-  Example: "🌮 Tacos are just sandwiches that think different / Implemented dependency injection for better testing"
-
-💭 SHOWER THOUGHTS: Those weird thoughts that actually fit
-  Example: "🚿 If you clean a vacuum cleaner, you're a vacuum cleaner / Removed unused imports and dead code"
-
-🎨 METAPHORS: Poetic descriptions of mundane changes
-  Example: "🌸 Like a butterfly emerging from its cache-rysalis / Optimized Redis caching strategy"
-
-RULES:
-1. MUST be contextually relevant to the actual code changes (even if loosely)
-2. First line: emoji + creative message (can be funny, deep, random, whatever)
-3. Second line: Clear technical explanation of what actually changed
-4. Use a single random emoji that fits the vibe (not limited to common ones)
-5. Be creative, be weird, be fun - but make it make sense when you squint
-6. Maximum 72 characters per line
-7. Don't use quotes around the output
-
-Git Changes:
-` + diff + `
-
-Generate the creative two-line commit message now:`
-
-	reqBody := GeminiRequest{
-		Contents: []Content{
-			{
-				Parts: []Part{
-					{Text: prompt},
-				},
-			},
-		},
-	}
-
-	jsonBody, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", err
-	}
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	req, err := http.NewRequest("POST", geminiURL+"?key="+apiKey, bytes.NewBuffer(jsonBody))
-	if err != nil {
-		return "", err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
+func generateCreativeCommit(ctx context.Context, provider Provider, diff string, style CommitStyle, commitType, scope, nudge string) (string, error) {
+	var prompt string
+	switch style {
+	case StyleConventional:
+		prompt = conventionalPrompt(diff, commitType, scope, nudge)
+	case StyleGitmoji:
+		prompt = gitmojiPrompt(diff, commitType, scope, nudge)
+	default:
+		prompt = chaoticPrompt(diff, nudge)
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	text, err := provider.Generate(ctx, prompt, GenerateOptions{})
 	if err != nil {
 		return "", err
 	}
 
-	var geminiResp GeminiResponse
-	if err := json.Unmarshal(body, &geminiResp); err != nil {
-		return "", err
-	}
-
-	if geminiResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", geminiResp.Error.Message)
-	}
-
-	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
-		return "", fmt.Errorf("no response from Gemini API")
-	}
-
-	commitMsg := strings.TrimSpace(geminiResp.Candidates[0].Content.Parts[0].Text)
+	commitMsg := strings.TrimSpace(text)
 	commitMsg = strings.Trim(commitMsg, "\"'`")
 
 	return commitMsg, nil
 }
-
-func copyToClipboard(text string) error {
-	var cmd *exec.Cmd
-
-	switch runtime.GOOS {
-	case "darwin":
-		cmd = exec.Command("pbcopy")
-	case "linux":
-		if _, err := exec.LookPath("xclip"); err == nil {
-			cmd = exec.Command("xclip", "-selection", "clipboard")
-		} else if _, err := exec.LookPath("xsel"); err == nil {
-			cmd = exec.Command("xsel", "--clipboard", "--input")
-		} else if _, err := exec.LookPath("wl-copy"); err == nil {
-			cmd = exec.Command("wl-copy")
-		} else {
-			return fmt.Errorf("no clipboard utility found")
-		}
-	case "windows":
-		cmd = exec.Command("cmd", "/c", "clip")
-	default:
-		return fmt.Errorf("unsupported OS: %s", runtime.GOOS)
-	}
-
-	cmd.Stdin = strings.NewReader(text)
-	return cmd.Run()
-}
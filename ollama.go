@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const (
+	defaultOllamaModel = "llama3"
+	ollamaURL          = "http://localhost:11434/api/generate"
+)
+
+type ollamaRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+	Stream bool   `json:"stream"`
+}
+
+type ollamaResponse struct {
+	Response string `json:"response"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ollamaProvider talks to a local Ollama daemon, letting genie-fun run
+// fully offline against a self-hosted model.
+type ollamaProvider struct{}
+
+func (p *ollamaProvider) Name() string { return "ollama" }
+
+func (p *ollamaProvider) Generate(ctx context.Context, prompt string, opts GenerateOptions) (string, error) {
+	model := opts.Model
+	if model == "" {
+		model = os.Getenv("OLLAMA_MODEL")
+	}
+	if model == "" {
+		model = defaultOllamaModel
+	}
+
+	reqBody := ollamaRequest{Model: model, Prompt: prompt, Stream: false}
+	jsonBody, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := doRequestWithRetry(ctx, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", ollamaURL, bytes.NewReader(jsonBody))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}, opts)
+	if err != nil {
+		return "", fmt.Errorf("ollama (is it running locally?): %w", err)
+	}
+
+	var resp ollamaResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return "", err
+	}
+	if resp.Error != "" {
+		return "", fmt.Errorf("ollama error: %s", resp.Error)
+	}
+
+	return resp.Response, nil
+}
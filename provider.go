@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// GenerateOptions carries the knobs that are common across providers.
+// Providers that don't support a given knob (e.g. Ollama has no
+// max-tokens concept in its simple /api/generate endpoint) ignore it.
+type GenerateOptions struct {
+	Model      string
+	MaxTokens  int
+	Timeout    time.Duration
+	MaxRetries int
+}
+
+func (o GenerateOptions) withDefaults() GenerateOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = 30 * time.Second
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = 2
+	}
+	return o
+}
+
+// Provider abstracts over a backend capable of turning a prompt into a
+// commit message. Implementations live one per file (gemini.go,
+// openai.go, anthropic.go, ollama.go).
+type Provider interface {
+	// Name identifies the provider for error messages and logging.
+	Name() string
+	// Generate sends prompt to the backend and returns its raw text
+	// response.
+	Generate(ctx context.Context, prompt string, opts GenerateOptions) (string, error)
+}
+
+const defaultProviderName = "gemini"
+
+// resolveProviderName picks the provider to use: --provider flag wins,
+// then GENIE_PROVIDER, then the gemini default for backwards
+// compatibility with existing setups.
+func resolveProviderName(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if env := os.Getenv("GENIE_PROVIDER"); env != "" {
+		return env
+	}
+	return defaultProviderName
+}
+
+// newProvider constructs the Provider for name, returning an error if
+// the name is unknown or its required API key env var is unset.
+func newProvider(name string) (Provider, error) {
+	switch name {
+	case "gemini":
+		apiKey := os.Getenv("GOOGLE_AI_TOKEN")
+		if apiKey == "" {
+			return nil, fmt.Errorf("GOOGLE_AI_TOKEN environment variable not set")
+		}
+		return &geminiProvider{apiKey: apiKey}, nil
+
+	case "openai":
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("OPENAI_API_KEY environment variable not set")
+		}
+		return &openAIProvider{apiKey: apiKey}, nil
+
+	case "anthropic":
+		apiKey := os.Getenv("ANTHROPIC_API_KEY")
+		if apiKey == "" {
+			return nil, fmt.Errorf("ANTHROPIC_API_KEY environment variable not set")
+		}
+		return &anthropicProvider{apiKey: apiKey}, nil
+
+	case "ollama":
+		return &ollamaProvider{}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown provider %q (want gemini, openai, anthropic, or ollama)", name)
+	}
+}
+
+// doRequestWithRetry executes req up to opts.MaxRetries+1 times with a
+// short linear backoff, returning the first successful (2xx) response
+// body. This is the shared retry/timeout policy every provider uses so
+// a flaky network blip on one backend doesn't surface as a hard failure.
+func doRequestWithRetry(ctx context.Context, newReq func(ctx context.Context) (*http.Request, error), opts GenerateOptions) ([]byte, error) {
+	opts = opts.withDefaults()
+	client := &http.Client{Timeout: opts.Timeout}
+
+	var lastErr error
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * 500 * time.Millisecond):
+			}
+		}
+
+		req, err := newReq(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode >= 500 {
+			lastErr = fmt.Errorf("server error %d: %s", resp.StatusCode, body)
+			continue
+		}
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("request failed with %d: %s", resp.StatusCode, body)
+		}
+
+		return body, nil
+	}
+
+	return nil, fmt.Errorf("request failed after %d attempts: %w", opts.MaxRetries+1, lastErr)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// repoOverrides is the shape of both the per-repo .genie-fun.json and
+// the global ~/.config/genie-fun/config.json: a lightweight style and
+// provider override that sits between the ~/.genie-fun.yaml Config and
+// the CLI flags in precedence.
+type repoOverrides struct {
+	Style    string `json:"style,omitempty"`
+	Provider string `json:"provider,omitempty"`
+}
+
+const repoConfigFilename = ".genie-fun.json"
+
+// globalConfigPath returns ~/.config/genie-fun/config.json (or
+// $XDG_CONFIG_HOME/genie-fun/config.json), following os.UserConfigDir.
+func globalConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "genie-fun", "config.json"), nil
+}
+
+// loadOverridesFile reads and parses a repoOverrides JSON file, treating
+// a missing file as "no overrides" rather than an error.
+func loadOverridesFile(path string) (*repoOverrides, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var o repoOverrides
+	if err := json.Unmarshal(data, &o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// applyRepoOverrides layers the per-repo .genie-fun.json and then the
+// global config.json on top of style/provider, with the repo file
+// taking precedence over the global one since mergeOverrides only fills
+// in a field once, so the repo's pass wins whatever it sets and the
+// global pass only fills in what's left. Either pointer is only
+// overwritten when the corresponding flag wasn't already set (i.e. its
+// string is still empty), so CLI flags always win. Unreadable or
+// malformed override files are silently ignored rather than failing the
+// whole command — they're a convenience, not a requirement.
+func applyRepoOverrides(style, provider *string) {
+	if o, err := loadOverridesFile(repoConfigFilename); err == nil && o != nil {
+		mergeOverrides(style, provider, o)
+	}
+
+	if global, err := globalConfigPath(); err == nil {
+		if o, err := loadOverridesFile(global); err == nil && o != nil {
+			mergeOverrides(style, provider, o)
+		}
+	}
+}
+
+func mergeOverrides(style, provider *string, o *repoOverrides) {
+	if *style == "" && o.Style != "" {
+		*style = o.Style
+	}
+	if *provider == "" && o.Provider != "" {
+		*provider = o.Provider
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+// nudgeSuffix turns an optional regeneration nudge ("make it weirder",
+// "more serious", ...) into an extra instruction appended to a prompt.
+func nudgeSuffix(nudge string) string {
+	if nudge == "" {
+		return ""
+	}
+	return "\n\nAdditional instruction from the user for this regeneration: " + nudge
+}
+
+// chaoticPrompt builds the original free-form, two-line creative prompt.
+func chaoticPrompt(diff, nudge string) string {
+	return `You are a creative, witty, and slightly chaotic developer who treats commit messages as an art form. You make commits that are fun, random, and creative - but ALWAYS contextually relevant to the actual code changes.
+
+YOUR MISSION:
+Analyze the git diff and create a TWO-LINE commit message:
+- Line 1: Random emoji + creative/funny/philosophical/lyrical message related to the change
+- Line 2: Actual technical explanation of what changed
+
+CREATIVE STYLES (pick randomly based on the vibe):
+🎵 SONG LYRICS: Find a song lyric that metaphorically relates to the change
+  Example: "🎸 I fought the law and the law won / Fixed authentication middleware to properly validate JWT tokens"
+
+🧠 PHILOSOPHICAL: Drop some wisdom that somehow connects
+  Example: "🌊 The only constant is change, except constants which I just changed / Refactored configuration values to environment variables"
+
+😂 JOKES/PUNS: Make a programming joke or pun about the change
+  Example: "🤡 Why did the function break up? It had too many arguments! / Simplified parameter passing in user service"
+
+🎭 RANDOM FACTS: Share a random fact that loosely relates
+  Example: "🦖 T-Rex couldn't clap but this code now can / Added applause animation to success notifications"
+
+🎪 CHAOS: Just pure creative chaos that somehow makes sense
+  Example: "🌮 Tacos are just sandwiches that think different / Implemented dependency injection for better testing"
+
+💭 SHOWER THOUGHTS: Those weird thoughts that actually fit
+  Example: "🚿 If you clean a vacuum cleaner, you're a vacuum cleaner / Removed unused imports and dead code"
+
+🎨 METAPHORS: Poetic descriptions of mundane changes
+  Example: "🌸 Like a butterfly emerging from its cache-rysalis / Optimized Redis caching strategy"
+
+RULES:
+1. MUST be contextually relevant to the actual code changes (even if loosely)
+2. First line: emoji + creative message (can be funny, deep, random, whatever)
+3. Second line: Clear technical explanation of what actually changed
+4. Use a single random emoji that fits the vibe (not limited to common ones)
+5. Be creative, be weird, be fun - but make it make sense when you squint
+6. Maximum 72 characters per line
+7. Don't use quotes around the output
+
+Git Changes:
+` + diff + `
+` + nudgeSuffix(nudge) + `
+
+Generate the creative two-line commit message now:`
+}
+
+// conventionalPrompt builds a prompt that asks for a Conventional Commits
+// formatted message, seeding the inferred type/scope as a hint the model
+// may refine but should rarely contradict.
+func conventionalPrompt(diff, commitType, scope, nudge string) string {
+	scopeHint := "no particular scope"
+	if scope != "" {
+		scopeHint = `"` + scope + `"`
+	}
+
+	return `You are an experienced developer writing a commit message that must satisfy commitlint's Conventional Commits rules.
+
+FORMAT (exactly):
+<type>(<scope>): <short description>
+
+<optional longer body>
+
+<optional "BREAKING CHANGE: <description>" footer, only if the diff removes or changes a public API/behavior in an incompatible way>
+
+RULES:
+1. <type> must be one of: feat, fix, refactor, chore, docs, test, perf
+2. <scope> is optional; omit the parentheses entirely if there isn't one
+3. <description> is lowercase, imperative mood, no trailing period, under 72 characters
+4. Only include a BREAKING CHANGE footer if it's actually warranted by the diff
+5. Don't use quotes or markdown around the output
+
+Heuristic analysis of this diff suggests type=` + commitType + `, scope=` + scopeHint + `. Use that as a starting point, but override it if the diff clearly says otherwise.
+
+Git Changes:
+` + diff + `
+` + nudgeSuffix(nudge) + `
+
+Generate the Conventional Commits message now:`
+}
+
+// gitmojiPrompt builds a prompt that asks for a Gitmoji-style message:
+// ":emoji_code: description".
+func gitmojiPrompt(diff, commitType, scope, nudge string) string {
+	return `You are an experienced developer writing a commit message in Gitmoji style.
+
+FORMAT (exactly one line):
+:emoji_code: <short description>
+
+Pick the emoji_code from the Gitmoji catalog based on the kind of change, for example:
+:sparkles: new feature        :bug: bug fix        :recycle: refactor
+:memo: documentation          :white_check_mark: tests   :zap: performance
+:wrench: chore/config
+
+RULES:
+1. Use the gitmoji :code: form (e.g. :sparkles:), not a literal emoji character
+2. <description> is lowercase, imperative mood, no trailing period, under 72 characters
+3. Exactly one line, no body, no quotes
+
+Heuristic analysis of this diff suggests this is closest to a "` + commitType + `" change` + scopeNote(scope) + `. Use that as a starting point, but override it if the diff clearly says otherwise.
+
+Git Changes:
+` + diff + `
+` + nudgeSuffix(nudge) + `
+
+Generate the Gitmoji commit message now:`
+}
+
+func scopeNote(scope string) string {
+	if scope == "" {
+		return ""
+	}
+	return ` in "` + scope + `"`
+}